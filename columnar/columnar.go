@@ -0,0 +1,105 @@
+// Package columnar plugs into jsoniter's Extension mechanism to move
+// "record batch" shaped data - a struct of equal-length slices - to and
+// from JSON as an array of row objects, without requiring callers to hand
+// write the row/column transposition for every schema.
+package columnar
+
+import (
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// RecordBatchOptions configures how a struct-of-slices type is treated as a
+// record batch.
+type RecordBatchOptions struct {
+	// TagKey is the struct tag used to name each column in the emitted row
+	// objects. Defaults to "json".
+	TagKey string
+	// NullMaskSuffix names the companion []bool field that marks a column's
+	// values as null row-by-row, e.g. a field "Age []int" paired with
+	// "AgeValid []bool". Defaults to "Valid".
+	NullMaskSuffix string
+}
+
+type column struct {
+	name      string
+	field     reflect2.StructField
+	maskField reflect2.StructField
+}
+
+type recordBatchCodec struct {
+	typ     reflect2.StructType
+	columns []*column
+}
+
+var registry = map[reflect2.Type]*recordBatchCodec{}
+
+// RegisterRecordBatch teaches jsoniter how to encode/decode a struct-of-slices
+// type as a JSON array of row objects. It must be called once per type
+// before that type is marshaled/unmarshaled through an API that has this
+// package's Extension installed (see NewExtension).
+func RegisterRecordBatch(typ reflect.Type, options RecordBatchOptions) {
+	if options.TagKey == "" {
+		options.TagKey = "json"
+	}
+	if options.NullMaskSuffix == "" {
+		options.NullMaskSuffix = "Valid"
+	}
+	structType := reflect2.Type2(typ).(reflect2.StructType)
+	codec := &recordBatchCodec{typ: structType}
+	fieldCount := structType.NumField()
+	maskFields := map[string]reflect2.StructField{}
+	for i := 0; i < fieldCount; i++ {
+		field := structType.Field(i)
+		name := field.Name()
+		if len(name) >= len(options.NullMaskSuffix) && name[len(name)-len(options.NullMaskSuffix):] == options.NullMaskSuffix {
+			maskFields[name[:len(name)-len(options.NullMaskSuffix)]] = field
+		}
+	}
+	for i := 0; i < fieldCount; i++ {
+		field := structType.Field(i)
+		if base := strings.TrimSuffix(field.Name(), options.NullMaskSuffix); base != field.Name() {
+			if _, hasBase := structType.Type1().FieldByName(base); hasBase {
+				continue
+			}
+		}
+		name := field.Name()
+		if tag, hasTag := field.Tag().Lookup(options.TagKey); hasTag && tag != "" {
+			name = tag
+		}
+		codec.columns = append(codec.columns, &column{
+			name:      name,
+			field:     field,
+			maskField: maskFields[field.Name()],
+		})
+	}
+	registry[reflect2.Type2(typ)] = codec
+}
+
+// NewExtension returns a jsoniter.Extension that encodes/decodes any type
+// previously passed to RegisterRecordBatch as a JSON array of row objects
+// instead of its natural struct-of-slices shape.
+func NewExtension() jsoniter.Extension {
+	return &recordBatchExtension{}
+}
+
+type recordBatchExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (ext *recordBatchExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if codec, found := registry[typ]; found {
+		return &recordBatchEncoder{codec: codec}
+	}
+	return nil
+}
+
+func (ext *recordBatchExtension) CreateDecoder(typ reflect2.Type) jsoniter.ValDecoder {
+	if codec, found := registry[typ]; found {
+		return &recordBatchDecoder{codec: codec}
+	}
+	return nil
+}
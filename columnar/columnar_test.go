@@ -0,0 +1,66 @@
+package columnar
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/json-iterator/go/require"
+)
+
+type Metrics struct {
+	Name       []string
+	Value      []int
+	ValueValid []bool
+}
+
+func Test_record_batch_round_trip(t *testing.T) {
+	should := require.New(t)
+	RegisterRecordBatch(reflect.TypeOf(Metrics{}), RecordBatchOptions{})
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(NewExtension())
+
+	batch := Metrics{
+		Name:       []string{"cpu", "mem"},
+		Value:      []int{1, 2},
+		ValueValid: []bool{true, false},
+	}
+	str, err := api.MarshalToString(batch)
+	should.Nil(err)
+	should.Equal(`[{"Name":"cpu","Value":1},{"Name":"mem","Value":null}]`, str)
+
+	var decoded Metrics
+	err = api.UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal([]string{"cpu", "mem"}, decoded.Name)
+	should.Equal([]bool{true, false}, decoded.ValueValid)
+}
+
+type Row struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func Test_record_encoder_and_reader(t *testing.T) {
+	should := require.New(t)
+	buf := &bytes.Buffer{}
+	enc := NewRecordEncoder(buf)
+	should.Nil(enc.WriteRow(Row{"cpu", 1}))
+	should.Nil(enc.WriteRow(Row{"mem", 2}))
+	should.Nil(enc.Close())
+	should.Equal(`[{"name":"cpu","value":1},{"name":"mem","value":2}]`, buf.String())
+
+	reader := NewRecordReader(bytes.NewReader(buf.Bytes()))
+	var rows []Row
+	for {
+		var row Row
+		err := reader.Next(&row)
+		if err != nil {
+			break
+		}
+		rows = append(rows, row)
+	}
+	should.Equal(2, len(rows))
+	should.Equal("cpu", rows[0].Name)
+}
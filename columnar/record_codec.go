@@ -0,0 +1,141 @@
+package columnar
+
+import (
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+type recordBatchEncoder struct {
+	codec *recordBatchCodec
+}
+
+func (encoder *recordBatchEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return rowCount(encoder.codec, ptr) == 0
+}
+
+func (encoder *recordBatchEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	n := rowCount(encoder.codec, ptr)
+	stream.WriteArrayStart()
+	for row := 0; row < n; row++ {
+		if row != 0 {
+			stream.WriteMore()
+		}
+		writeRow(encoder.codec, ptr, row, stream)
+	}
+	stream.WriteArrayEnd()
+}
+
+type recordBatchDecoder struct {
+	codec *recordBatchCodec
+}
+
+func (decoder *recordBatchDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	resetColumns(decoder.codec, ptr)
+	iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+		appendRow(decoder.codec, ptr, iter)
+		return true
+	})
+}
+
+func columnSliceType(col *column) reflect2.SliceType {
+	return col.field.Type().(reflect2.SliceType)
+}
+
+func rowCount(codec *recordBatchCodec, ptr unsafe.Pointer) int {
+	if len(codec.columns) == 0 {
+		return 0
+	}
+	col := codec.columns[0]
+	return columnSliceType(col).UnsafeLengthOf(col.field.UnsafeGet(ptr))
+}
+
+func resetColumns(codec *recordBatchCodec, ptr unsafe.Pointer) {
+	for _, col := range codec.columns {
+		columnSliceType(col).UnsafeSetNil(col.field.UnsafeGet(ptr))
+		if col.maskField != nil {
+			maskType := col.maskField.Type().(reflect2.SliceType)
+			maskType.UnsafeSetNil(col.maskField.UnsafeGet(ptr))
+		}
+	}
+}
+
+func writeRow(codec *recordBatchCodec, ptr unsafe.Pointer, row int, stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	for i, col := range codec.columns {
+		if i != 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(col.name)
+		if col.maskField != nil && !rowValid(col, ptr, row) {
+			stream.WriteNil()
+			continue
+		}
+		sliceType := columnSliceType(col)
+		elemPtr := sliceType.UnsafeGetIndex(col.field.UnsafeGet(ptr), row)
+		stream.WriteVal(sliceType.Elem().PackEFace(elemPtr))
+	}
+	stream.WriteObjectEnd()
+}
+
+func rowValid(col *column, ptr unsafe.Pointer, row int) bool {
+	maskType := col.maskField.Type().(reflect2.SliceType)
+	maskSlicePtr := col.maskField.UnsafeGet(ptr)
+	if row >= maskType.UnsafeLengthOf(maskSlicePtr) {
+		return true
+	}
+	return *(*bool)(maskType.UnsafeGetIndex(maskSlicePtr, row))
+}
+
+func appendRow(codec *recordBatchCodec, ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	for _, col := range codec.columns {
+		sliceType := columnSliceType(col)
+		fieldPtr := col.field.UnsafeGet(ptr)
+		length := sliceType.UnsafeLengthOf(fieldPtr)
+		sliceType.UnsafeGrow(fieldPtr, length+1)
+		if col.maskField != nil {
+			maskType := col.maskField.Type().(reflect2.SliceType)
+			maskFieldPtr := col.maskField.UnsafeGet(ptr)
+			maskType.UnsafeGrow(maskFieldPtr, sliceType.UnsafeLengthOf(fieldPtr))
+		}
+	}
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		col := findColumn(codec, field)
+		if col == nil {
+			iter.Skip()
+			return true
+		}
+		sliceType := columnSliceType(col)
+		fieldPtr := col.field.UnsafeGet(ptr)
+		row := sliceType.UnsafeLengthOf(fieldPtr) - 1
+		if iter.WhatIsNext() == jsoniter.Nil {
+			iter.ReadNil()
+			if col.maskField != nil {
+				setValid(col, ptr, row, false)
+			}
+			return true
+		}
+		elemPtr := sliceType.UnsafeGetIndex(fieldPtr, row)
+		iter.ReadVal(sliceType.Elem().PackEFace(elemPtr))
+		if col.maskField != nil {
+			setValid(col, ptr, row, true)
+		}
+		return true
+	})
+}
+
+func setValid(col *column, ptr unsafe.Pointer, row int, valid bool) {
+	maskType := col.maskField.Type().(reflect2.SliceType)
+	maskFieldPtr := col.maskField.UnsafeGet(ptr)
+	*(*bool)(maskType.UnsafeGetIndex(maskFieldPtr, row)) = valid
+}
+
+func findColumn(codec *recordBatchCodec, name string) *column {
+	for _, col := range codec.columns {
+		if col.name == name {
+			return col
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package columnar
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// RecordEncoder writes one row at a time to an underlying io.Writer, so a
+// large batch never has to be buffered in full before being sent out.
+type RecordEncoder struct {
+	stream *jsoniter.Stream
+	n      int
+}
+
+// NewRecordEncoder returns a RecordEncoder that writes a JSON array of row
+// objects to w, one WriteRow call at a time. Call Close when done to emit
+// the closing bracket.
+func NewRecordEncoder(w io.Writer) *RecordEncoder {
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, w, 4096)
+	stream.WriteArrayStart()
+	return &RecordEncoder{stream: stream}
+}
+
+// WriteRow encodes row, a single struct value (not a struct-of-slices), as
+// the next element of the array and flushes it to the writer.
+func (enc *RecordEncoder) WriteRow(row interface{}) error {
+	if enc.n != 0 {
+		enc.stream.WriteMore()
+	}
+	enc.n++
+	enc.stream.WriteVal(row)
+	if enc.stream.Error != nil {
+		return enc.stream.Error
+	}
+	return enc.stream.Flush()
+}
+
+// Close emits the closing `]` and flushes the underlying writer.
+func (enc *RecordEncoder) Close() error {
+	enc.stream.WriteArrayEnd()
+	return enc.stream.Flush()
+}
+
+// RecordReader parses a JSON array of row objects from an io.Reader one row
+// at a time, so a multi-GB feed is never held in memory all at once.
+type RecordReader struct {
+	iter *jsoniter.Iterator
+}
+
+// NewRecordReader returns a RecordReader reading a top-level JSON array from
+// r.
+func NewRecordReader(r io.Reader) *RecordReader {
+	return &RecordReader{iter: jsoniter.Parse(jsoniter.ConfigDefault, r, 4096)}
+}
+
+// Next decodes the next row into rowPtr, a pointer to a struct matching one
+// row's shape. It returns io.EOF once the array is exhausted.
+func (rr *RecordReader) Next(rowPtr interface{}) error {
+	if !rr.iter.ReadArray() {
+		return io.EOF
+	}
+	rr.iter.ReadVal(rowPtr)
+	return rr.iter.Error
+}
@@ -0,0 +1,152 @@
+package jsoniter
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Config customizes how the API should behave. The API is created from
+// Config by Froze.
+type Config struct {
+	IndentionStep                 int
+	MarshalFloatWith6Digits       bool
+	EscapeHTML                    bool
+	SortMapKeys                   bool
+	UseNumber                     bool
+	DisallowUnknownFields         bool
+	TagKey                        string
+	OnlyTaggedField               bool
+	ValidateJsonRawMessage        bool
+	ObjectFieldMustBeSimpleString bool
+	CaseSensitive                 bool
+	// TimeLayout, when Mode is TimeModeCustom, is the time.Time layout used
+	// to format/parse. Ignored for the other modes.
+	TimeLayout string
+	// TimeLocation is the time zone time.Time values are formatted in, the
+	// zone the Unix modes decode into, and the zone assumed when parsing a
+	// string whose layout carries no zone of its own. Defaults to time.UTC
+	// when nil.
+	TimeLocation *time.Location
+	// TimeMode selects how time.Time is encoded/decoded by this Config's
+	// API. Defaults to TimeModeRFC3339.
+	TimeMode TimeMode
+	// TimeParseLayouts is tried, in order, when decoding a time.Time string
+	// that doesn't parse with TimeLayout (TimeModeCustom) or RFC3339
+	// (TimeModeRFC3339). The Unix modes never consult it: any string other
+	// than "" is an error there. Useful for APIs ingesting heterogeneous
+	// timestamp formats.
+	TimeParseLayouts []string
+	// TimeZeroValue controls how a zero time.Time is encoded. Defaults to
+	// TimeZeroNull.
+	TimeZeroValue TimeZeroValue
+}
+
+// TimeZeroValue selects the JSON representation of a zero time.Time.
+type TimeZeroValue int
+
+const (
+	// TimeZeroNull encodes a zero time.Time as JSON null (the default).
+	TimeZeroNull TimeZeroValue = iota
+	// TimeZeroEmptyString encodes a zero time.Time as "", instead of null.
+	// Decoding "" back always yields time.Time{}, regardless of TimeMode.
+	TimeZeroEmptyString
+)
+
+// TimeMode selects the wire representation used for time.Time by a Config's
+// installed codec.
+type TimeMode int
+
+const (
+	// TimeModeRFC3339 formats/parses using time.RFC3339Nano (the default).
+	TimeModeRFC3339 TimeMode = iota
+	// TimeModeUnix encodes as a JSON number of whole seconds since epoch.
+	TimeModeUnix
+	// TimeModeUnixMilli encodes as a JSON number of milliseconds since epoch.
+	TimeModeUnixMilli
+	// TimeModeUnixNano encodes as a JSON number of nanoseconds since epoch.
+	TimeModeUnixNano
+	// TimeModeCustom formats/parses using Config.TimeLayout.
+	TimeModeCustom
+)
+
+// API is the set of APIs provided by an instance frozen from a Config.
+type API interface {
+	IteratorPool
+	StreamPool
+	MarshalToString(v interface{}) (string, error)
+	Marshal(v interface{}) ([]byte, error)
+	MarshalIndent(v interface{}, prefix, indent string) ([]byte, error)
+	UnmarshalFromString(str string, v interface{}) error
+	Unmarshal(data []byte, v interface{}) error
+	Get(data []byte, path ...interface{}) Any
+	NewEncoder(writer io.Writer) *Encoder
+	NewDecoder(reader io.Reader) *Decoder
+	Valid(data []byte) bool
+	RegisterExtension(extension Extension)
+	DecoderOf(typ reflect2.Type) ValDecoder
+	EncoderOf(typ reflect2.Type) ValEncoder
+}
+
+// ConfigDefault is the default API, roughly matching encoding/json's
+// defaults except for HTML escaping, which jsoniter enables by default.
+var ConfigDefault = Config{
+	EscapeHTML: true,
+}.Froze()
+
+type frozenConfig struct {
+	configBeforeFrozen Config
+	tagKey             string
+	extraExtensions    []Extension
+	streamPool         *sync.Pool
+	iteratorPool       *sync.Pool
+	cfgCache           *sync.Map
+	timeEncoder        ValEncoder
+	timeDecoder        ValDecoder
+}
+
+// Froze creates an API instance from cfg. Each instance gets its own
+// extensions and, per chunk0-4, its own time.Time codec derived from
+// cfg.TimeMode/TimeLayout/TimeLocation/TimeParseLayouts, so multiple Configs
+// no longer have to fight over a single global RegisterTypeEncoderFunc. The
+// codec is installed as an extraExtensions entry scoped to time.Time itself,
+// so a distinct wrapper type (e.g. `type myTime time.Time`) implementing its
+// own MarshalJSON/UnmarshalJSON is untouched and keeps taking precedence.
+func (cfg Config) Froze() *frozenConfig {
+	api := &frozenConfig{
+		configBeforeFrozen: cfg,
+		streamPool:         new(sync.Pool),
+		iteratorPool:       new(sync.Pool),
+		cfgCache:           new(sync.Map),
+	}
+	api.tagKey = cfg.TagKey
+	if api.tagKey == "" {
+		api.tagKey = "json"
+	}
+	api.timeEncoder, api.timeDecoder = newTimeCodec(cfg)
+	api.RegisterExtension(newTimeCodecExtension(api.timeEncoder, api.timeDecoder))
+	return api
+}
+
+// RegisterExtension adds extension to this API instance only, ahead of any
+// process-wide extensions registered via the package-level RegisterExtension.
+func (cfg *frozenConfig) RegisterExtension(extension Extension) {
+	cfg.extraExtensions = append(cfg.extraExtensions, extension)
+}
+
+// cleanDecoders clears every type/field decoder registered via
+// RegisterTypeDecoderFunc/RegisterFieldDecoderFunc. Those registrations are
+// process-wide, so this affects every API instance, not only cfg. It exists
+// for test isolation.
+func (cfg *frozenConfig) cleanDecoders() {
+	typeDecoders = map[string]ValDecoder{}
+	fieldDecoders = map[string]ValDecoder{}
+}
+
+// cleanEncoders is the encode-side counterpart of cleanDecoders.
+func (cfg *frozenConfig) cleanEncoders() {
+	typeEncoders = map[string]ValEncoder{}
+	fieldEncoders = map[string]ValEncoder{}
+}
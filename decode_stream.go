@@ -0,0 +1,119 @@
+package jsoniter
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/modern-go/reflect2"
+)
+
+// DecodeOptions controls the behavior of Decode.
+type DecodeOptions struct {
+	// ReentrantCallback, when set, is invoked once per token produced while
+	// walking data: once for each array/object boundary and once for each
+	// scalar/key. path is a JSON-pointer style location ("/items/3/name"):
+	// a scalar or an opening token is reported at its own location, a key at
+	// the location of the object holding it, and a TokenEnd at the location
+	// of the container it closes. When ReentrantCallback is set, Decode does
+	// not also decode data into v - the callback is the only consumer, so a
+	// multi-GB document is never held in memory as a whole.
+	ReentrantCallback func(path string, token Token)
+}
+
+// Decode reads a single JSON value from data. With no ReentrantCallback set,
+// it behaves exactly like Unmarshal(data, v). With one set, it instead walks
+// data as a single pass of tokens, invoking the callback with each token's
+// JSON-pointer path, and leaves v untouched - that single pass is the whole
+// point: the caller's callback can offload each element to disk/DB and drop
+// it from memory as it goes, instead of jsoniter materializing the full
+// document into v first.
+func Decode(data []byte, v interface{}, opts DecodeOptions) error {
+	if opts.ReentrantCallback != nil {
+		iter := ParseBytes(ConfigDefault, data)
+		walkWithCallback(iter, opts.ReentrantCallback)
+		if iter.Error != nil && !errors.Is(iter.Error, io.EOF) {
+			return iter.Error
+		}
+		return nil
+	}
+	return ConfigDefault.Unmarshal(data, v)
+}
+
+func walkWithCallback(iter *Iterator, cb func(string, Token)) {
+	ts := iter.Tokens()
+	token, ok := ts.Next()
+	if !ok {
+		return
+	}
+	walkValue(ts, "", token, cb)
+}
+
+// walkValue reports token (already read at path) to cb, then, if token opens
+// a container, keeps pulling from ts and recursing until that container's
+// matching TokenEnd, building up the JSON-pointer path as it descends.
+func walkValue(ts *TokenStream, path string, token Token, cb func(string, Token)) {
+	cb(path, token)
+	switch token.Kind {
+	case TokenBeginObject:
+		for {
+			keyToken, ok := ts.Next()
+			if !ok {
+				return
+			}
+			if keyToken.Kind == TokenEnd {
+				cb(path, keyToken)
+				return
+			}
+			cb(path, keyToken)
+			valueToken, ok := ts.Next()
+			if !ok {
+				return
+			}
+			walkValue(ts, path+"/"+keyToken.Value.(string), valueToken, cb)
+		}
+	case TokenBeginArray:
+		index := 0
+		for {
+			elemToken, ok := ts.Next()
+			if !ok {
+				return
+			}
+			if elemToken.Kind == TokenEnd {
+				cb(path, elemToken)
+				return
+			}
+			walkValue(ts, path+"/"+strconv.Itoa(index), elemToken, cb)
+			index++
+		}
+	}
+}
+
+// StreamingDecoder is implemented by types that want to consume a
+// TokenStream directly instead of an *Iterator, so that each element of a
+// large array or object can be handed off (e.g. to disk or a database) and
+// dropped from memory as soon as it is read.
+type StreamingDecoder interface {
+	DecodeTokens(ts *TokenStream) error
+}
+
+var streamingDecoders = map[reflect2.Type]StreamingDecoder{}
+
+// RegisterStreamingDecoder registers a StreamingDecoder for typ, to be used
+// whenever that type is decoded via DecodeStreaming.
+func RegisterStreamingDecoder(typ reflect.Type, decoder StreamingDecoder) {
+	streamingDecoders[reflect2.Type2(typ)] = decoder
+}
+
+// DecodeStreaming behaves like Decode, except that if a StreamingDecoder has
+// been registered for the type v points to, it is handed the token stream directly instead
+// of the value being decoded through the normal reflection-based path.
+func DecodeStreaming(data []byte, v interface{}) error {
+	typ := reflect2.TypeOf(v).Elem()
+	if decoder, found := streamingDecoders[typ]; found {
+		iter := ParseBytes(ConfigDefault, data)
+		return decoder.DecodeTokens(iter.Tokens())
+	}
+	return ConfigDefault.Unmarshal(data, v)
+}
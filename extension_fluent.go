@@ -0,0 +1,287 @@
+package jsoniter
+
+import (
+	"regexp"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// FluentExtension is a higher-level builder over Extension for the common
+// cases of testExtension-style UpdateStructDescriptor code: renaming a
+// field, adding a computed/virtual field, conditionally omitting a field,
+// and applying a naming convention across many types at once. It compiles
+// down to the same Binding/funcEncoder wiring a hand-written Extension would
+// use, plus a DecorateEncoder wrapper for OmitWhen; it installs no decoders
+// of its own.
+type FluentExtension struct {
+	DummyExtension
+	renames   map[string]string
+	computed  []computedField
+	omitWhen  map[string]func(ptr unsafe.Pointer) bool
+	caseRules []caseRule
+	// omitRules is filled in by UpdateStructDescriptor (once per concrete
+	// struct type it sees) and consumed by DecorateEncoder, which is the
+	// only hook that runs unconditionally - unlike a plain IsEmpty override,
+	// it isn't gated behind the field's own `,omitempty` tag.
+	omitRules map[reflect2.Type][]omitRule
+}
+
+type computedField struct {
+	name string
+	fun  func(ptr unsafe.Pointer) interface{}
+}
+
+type caseRule struct {
+	typePattern *regexp.Regexp
+	transform   func(string) string
+}
+
+// NewFluentExtension returns an empty FluentExtension ready to be
+// configured with Rename/Compute/OmitWhen/CaseStyle and then registered
+// with RegisterExtension.
+func NewFluentExtension() *FluentExtension {
+	return &FluentExtension{
+		renames:   map[string]string{},
+		omitWhen:  map[string]func(ptr unsafe.Pointer) bool{},
+		omitRules: map[reflect2.Type][]omitRule{},
+	}
+}
+
+// Rename declares that the Go field named from should be read from and
+// written to the JSON key to instead of its default name.
+func (ext *FluentExtension) Rename(from, to string) *FluentExtension {
+	ext.renames[from] = to
+	return ext
+}
+
+// Compute adds a virtual output field named name, whose value is produced
+// by fun(ptr) at encode time. It has no backing Go field and is never
+// decoded into. Compute has no effect on a struct type with zero fields,
+// since there is no field to anchor the computed value's pointer on.
+func (ext *FluentExtension) Compute(name string, fun func(ptr unsafe.Pointer) interface{}) *FluentExtension {
+	ext.computed = append(ext.computed, computedField{name: name, fun: fun})
+	return ext
+}
+
+// OmitWhen skips emitting field whenever shouldOmit(ptr) returns true,
+// regardless of whether field also carries a `,omitempty` tag: the check
+// runs unconditionally via DecorateEncoder, not by piggybacking on the
+// struct encoder's tag-gated omitempty path.
+func (ext *FluentExtension) OmitWhen(field string, shouldOmit func(ptr unsafe.Pointer) bool) *FluentExtension {
+	ext.omitWhen[field] = shouldOmit
+	return ext
+}
+
+// CaseStyle registers one of the built-in case-transform policies
+// ("snake", "camel", "kebab") to be applied to every field of every type
+// whose name matches typeNamePattern.
+func (ext *FluentExtension) CaseStyle(typeNamePattern, style string) *FluentExtension {
+	ext.caseRules = append(ext.caseRules, caseRule{
+		typePattern: regexp.MustCompile(typeNamePattern),
+		transform:   caseTransform(style),
+	})
+	return ext
+}
+
+// UpdateStructDescriptor implements Extension by applying every rule
+// configured on ext to structDescriptor's bindings.
+func (ext *FluentExtension) UpdateStructDescriptor(structDescriptor *StructDescriptor) {
+	typeName := structDescriptor.Type.String()
+	for _, binding := range structDescriptor.Fields {
+		fieldName := binding.Field.Name()
+		if to, found := ext.renames[fieldName]; found {
+			binding.FromNames = []string{to}
+			binding.ToNames = []string{to}
+		} else {
+			for _, rule := range ext.caseRules {
+				if !rule.typePattern.MatchString(typeName) {
+					continue
+				}
+				name := rule.transform(fieldName)
+				binding.FromNames = []string{name}
+				binding.ToNames = []string{name}
+				break
+			}
+		}
+		if shouldOmit, found := ext.omitWhen[fieldName]; found {
+			if structField, found := structDescriptor.Type.Type1().FieldByName(fieldName); found {
+				ext.omitRules[structDescriptor.Type] = append(ext.omitRules[structDescriptor.Type], omitRule{
+					jsonKey:    binding.ToNames[0],
+					offset:     structField.Offset,
+					shouldOmit: shouldOmit,
+				})
+			}
+		}
+	}
+	if len(ext.computed) > 0 && len(structDescriptor.Fields) > 0 {
+		// Computed fields have no backing storage of their own; anchoring
+		// them on the first real field (offset 0) means the pointer handed
+		// to their encoder is the struct's own base pointer, which is what
+		// a Compute func expects.
+		anchor := structDescriptor.Fields[0].Field
+		for _, field := range ext.computed {
+			structDescriptor.Fields = append(structDescriptor.Fields, &Binding{
+				Field:   anchor,
+				ToNames: []string{field.name},
+				Encoder: &funcEncoder{fun: computedEncoder(field.fun)},
+			})
+		}
+	}
+}
+
+func computedEncoder(fun func(ptr unsafe.Pointer) interface{}) func(unsafe.Pointer, *Stream) {
+	return func(ptr unsafe.Pointer, stream *Stream) {
+		stream.WriteVal(fun(ptr))
+	}
+}
+
+// omitRule is one OmitWhen rule resolved against a concrete struct type:
+// offset locates the field's own pointer from the struct's base pointer, so
+// shouldOmit sees exactly the ptr an IsEmpty check on that field would.
+type omitRule struct {
+	jsonKey    string
+	offset     uintptr
+	shouldOmit func(ptr unsafe.Pointer) bool
+}
+
+// DecorateEncoder wraps typ's compiled struct encoder with a filter for any
+// OmitWhen rules registered against it. This runs for every struct encoder
+// unconditionally, which is what makes OmitWhen take effect regardless of
+// whether the field also carries a `,omitempty` tag.
+func (ext *FluentExtension) DecorateEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	rules := ext.omitRules[typ]
+	if len(rules) == 0 {
+		return encoder
+	}
+	return &structOmitEncoder{elemEncoder: encoder, rules: rules}
+}
+
+type structOmitEncoder struct {
+	elemEncoder ValEncoder
+	rules       []omitRule
+}
+
+func (encoder *structOmitEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.elemEncoder.IsEmpty(ptr)
+}
+
+func (encoder *structOmitEncoder) EncodeInterface(val interface{}, stream *Stream) {
+	WriteToStream(val, stream, encoder)
+}
+
+// Encode renders ptr through the normal struct encoder into a scratch
+// stream, then strips out any field whose rule fired, before copying the
+// result into stream. Fields that never omit anything take the fast path
+// and skip the scratch buffer entirely.
+func (encoder *structOmitEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	omit := map[string]bool{}
+	for _, rule := range encoder.rules {
+		fieldPtr := unsafe.Pointer(uintptr(ptr) + rule.offset)
+		if rule.shouldOmit(fieldPtr) {
+			omit[rule.jsonKey] = true
+		}
+	}
+	if len(omit) == 0 {
+		encoder.elemEncoder.Encode(ptr, stream)
+		return
+	}
+	tempStream := NewStream(stream.cfg, nil, 64)
+	encoder.elemEncoder.Encode(ptr, tempStream)
+	if tempStream.Error != nil {
+		stream.Error = tempStream.Error
+		return
+	}
+	stream.Write(filterObjectFields(stream.cfg, tempStream.Buffer(), omit))
+}
+
+// filterObjectFields re-encodes the top-level object in data with every key
+// in omit (and its value) dropped.
+func filterObjectFields(cfg *frozenConfig, data []byte, omit map[string]bool) []byte {
+	iter := ParseBytes(cfg, data)
+	out := NewStream(cfg, nil, len(data))
+	out.WriteObjectStart()
+	first := true
+	for field := iter.ReadObject(); field != ""; field = iter.ReadObject() {
+		if omit[field] {
+			iter.Skip()
+			continue
+		}
+		if !first {
+			out.WriteMore()
+		}
+		first = false
+		out.WriteObjectField(field)
+		out.WriteVal(iter.Read())
+	}
+	out.WriteObjectEnd()
+	return out.Buffer()
+}
+
+func caseTransform(style string) func(string) string {
+	switch style {
+	case "snake":
+		return toSnakeCase
+	case "kebab":
+		return toKebabCase
+	case "camel":
+		return toCamelCase
+	default:
+		return func(s string) string { return s }
+	}
+}
+
+func toSnakeCase(s string) string {
+	return toDelimitedCase(s, '_')
+}
+
+func toKebabCase(s string) string {
+	return toDelimitedCase(s, '-')
+}
+
+func toCamelCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func toDelimitedCase(s string, sep byte) string {
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' {
+			if i != 0 {
+				out = append(out, sep)
+			}
+			c = c - 'A' + 'a'
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// RegisterExtensionForTypes registers ext so that its UpdateStructDescriptor
+// only runs for struct types whose full name matches typeNamePattern,
+// sparing callers from writing that type-name check themselves. ext's other
+// Extension methods (CreateEncoder, DecorateEncoder and so on) are still
+// called for every type.
+func RegisterExtensionForTypes(typeNamePattern string, ext Extension) {
+	RegisterExtension(&scopedExtension{
+		pattern:   regexp.MustCompile(typeNamePattern),
+		Extension: ext,
+	})
+}
+
+type scopedExtension struct {
+	Extension
+	pattern *regexp.Regexp
+}
+
+func (ext *scopedExtension) UpdateStructDescriptor(structDescriptor *StructDescriptor) {
+	if !ext.pattern.MatchString(structDescriptor.Type.String()) {
+		return
+	}
+	ext.Extension.UpdateStructDescriptor(structDescriptor)
+}
@@ -0,0 +1,126 @@
+package jsoniter
+
+// TokenKind identifies the shape of a Token produced by Iterator.Tokens().
+type TokenKind int
+
+const (
+	TokenInvalid TokenKind = iota
+	TokenBeginObject
+	TokenKey
+	TokenBeginArray
+	TokenString
+	TokenNumber
+	TokenBool
+	TokenNull
+	TokenEnd
+)
+
+// Token is a single element of a pull-based token stream. Value is populated
+// for TokenKey, TokenString, TokenNumber and TokenBool; for TokenEnd it holds
+// the TokenKind (TokenBeginObject or TokenBeginArray) of the container that
+// just closed. It is nil otherwise.
+type Token struct {
+	Kind  TokenKind
+	Value interface{}
+}
+
+// tokenFrame tracks one open container on the TokenStream's stack. started
+// is only meaningful for TokenBeginObject: it distinguishes the first field
+// (read via readObjectStart) from later ones (read after a ',').
+type tokenFrame struct {
+	kind    TokenKind
+	started bool
+}
+
+// TokenStream pulls tokens out of an Iterator one at a time without ever
+// materializing the whole document in memory. It is created by
+// Iterator.Tokens() and is only valid for as long as the underlying
+// Iterator is valid.
+type TokenStream struct {
+	iter  *Iterator
+	stack []tokenFrame
+	// done is set once the top-level value has been started, so that the
+	// stream ends after that value instead of reading past it.
+	done bool
+}
+
+// Tokens returns a pull-based token stream over the JSON value the iterator
+// is currently positioned on. Reading from the returned TokenStream advances
+// iter.
+func (iter *Iterator) Tokens() *TokenStream {
+	return &TokenStream{iter: iter}
+}
+
+func (ts *TokenStream) push(kind TokenKind) {
+	ts.stack = append(ts.stack, tokenFrame{kind: kind})
+}
+
+func (ts *TokenStream) pop() {
+	ts.stack = ts.stack[:len(ts.stack)-1]
+}
+
+// Next returns the next token in the stream. When the stream is exhausted it
+// returns ok == false; ts.iter.Error should be inspected to distinguish a
+// clean end from a parse error.
+func (ts *TokenStream) Next() (token Token, ok bool) {
+	iter := ts.iter
+	if len(ts.stack) == 0 {
+		if ts.done {
+			return Token{}, false
+		}
+		ts.done = true
+		return ts.readValue()
+	}
+	top := &ts.stack[len(ts.stack)-1]
+	switch top.kind {
+	case TokenKey:
+		ts.pop()
+		return ts.readValue()
+	case TokenBeginObject:
+		var hasNext bool
+		if !top.started {
+			top.started = true
+			hasNext = iter.readObjectStart()
+		} else {
+			hasNext = iter.nextToken() == ','
+		}
+		if !hasNext {
+			ts.pop()
+			return Token{Kind: TokenEnd, Value: TokenBeginObject}, true
+		}
+		field := iter.readObjectFieldAsBytes()
+		ts.push(TokenKey)
+		return Token{Kind: TokenKey, Value: string(field)}, true
+	case TokenBeginArray:
+		if !iter.ReadArray() {
+			ts.pop()
+			return Token{Kind: TokenEnd, Value: TokenBeginArray}, true
+		}
+		return ts.readValue()
+	}
+	return ts.readValue()
+}
+
+func (ts *TokenStream) readValue() (Token, bool) {
+	iter := ts.iter
+	switch iter.WhatIsNext() {
+	case Object:
+		ts.push(TokenBeginObject)
+		return Token{Kind: TokenBeginObject}, true
+	case Array:
+		ts.push(TokenBeginArray)
+		return Token{Kind: TokenBeginArray}, true
+	case String:
+		return Token{Kind: TokenString, Value: iter.ReadString()}, true
+	case Number:
+		return Token{Kind: TokenNumber, Value: iter.ReadFloat64()}, true
+	case Bool:
+		return Token{Kind: TokenBool, Value: iter.ReadBool()}, true
+	case Nil:
+		iter.ReadNil()
+		return Token{Kind: TokenNull}, true
+	default:
+		iter.ReportError("Tokens", "unexpected token")
+		return Token{}, false
+	}
+}
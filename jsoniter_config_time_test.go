@@ -0,0 +1,78 @@
+package jsoniter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go/require"
+)
+
+func Test_config_time_rfc3339_default(t *testing.T) {
+	should := require.New(t)
+	api := Config{}.Froze()
+	val := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	str, err := api.MarshalToString(val)
+	should.Nil(err)
+	should.Equal(`"2020-01-02T03:04:05Z"`, str)
+}
+
+func Test_config_time_unix_mode(t *testing.T) {
+	should := require.New(t)
+	api := Config{TimeMode: TimeModeUnix}.Froze()
+	val := time.Unix(1500000000, 0).UTC()
+	str, err := api.MarshalToString(val)
+	should.Nil(err)
+	should.Equal(`1500000000`, str)
+
+	var decoded time.Time
+	err = api.UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal(int64(1500000000), decoded.Unix())
+}
+
+func Test_config_time_custom_layout(t *testing.T) {
+	should := require.New(t)
+	api := Config{
+		TimeMode:   TimeModeCustom,
+		TimeLayout: "2006-01-02 15:04:05",
+	}.Froze()
+	str, err := api.MarshalToString(time.Date(2016, 12, 5, 8, 43, 28, 0, time.UTC))
+	should.Nil(err)
+	should.Equal(`"2016-12-05 08:43:28"`, str)
+}
+
+func Test_config_time_zero_value_emits_null(t *testing.T) {
+	should := require.New(t)
+	api := Config{}.Froze()
+	str, err := api.MarshalToString(time.Time{})
+	should.Nil(err)
+	should.Equal(`null`, str)
+}
+
+func Test_config_time_zero_value_emits_empty_string(t *testing.T) {
+	should := require.New(t)
+	api := Config{TimeZeroValue: TimeZeroEmptyString}.Froze()
+	str, err := api.MarshalToString(time.Time{})
+	should.Nil(err)
+	should.Equal(`""`, str)
+
+	var decoded time.Time
+	err = api.UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal(true, decoded.IsZero())
+}
+
+func Test_config_time_two_configs_do_not_interfere(t *testing.T) {
+	should := require.New(t)
+	unixAPI := Config{TimeMode: TimeModeUnix}.Froze()
+	rfcAPI := Config{}.Froze()
+	val := time.Unix(1500000000, 0).UTC()
+
+	unixStr, err := unixAPI.MarshalToString(val)
+	should.Nil(err)
+	should.Equal(`1500000000`, unixStr)
+
+	rfcStr, err := rfcAPI.MarshalToString(val)
+	should.Nil(err)
+	should.Equal(`"2017-07-14T02:40:00Z"`, rfcStr)
+}
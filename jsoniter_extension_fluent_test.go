@@ -0,0 +1,75 @@
+package jsoniter
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/json-iterator/go/require"
+)
+
+type fluentRenamePerson struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func Test_fluent_extension_rename(t *testing.T) {
+	should := require.New(t)
+	ext := NewFluentExtension().Rename("FirstName", "first_name")
+	RegisterExtension(ext)
+	obj := fluentRenamePerson{FirstName: "Ada", LastName: "Lovelace", Age: 36}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Contains(str, `"first_name":"Ada"`)
+}
+
+type fluentOmitPerson struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func Test_fluent_extension_omit_when(t *testing.T) {
+	should := require.New(t)
+	ext := NewFluentExtension().OmitWhen("Age", func(ptr unsafe.Pointer) bool {
+		return *(*int)(ptr) == 0
+	})
+	RegisterExtension(ext)
+	obj := fluentOmitPerson{FirstName: "Ada", LastName: "Lovelace", Age: 0}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.NotContains(str, "Age")
+}
+
+type fluentCaseStylePerson struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func Test_fluent_extension_case_style(t *testing.T) {
+	should := require.New(t)
+	ext := NewFluentExtension().CaseStyle("jsoniter.fluentCaseStylePerson", "snake")
+	RegisterExtension(ext)
+	obj := fluentCaseStylePerson{FirstName: "Ada", LastName: "Lovelace", Age: 36}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Contains(str, `"first_name"`)
+	should.Contains(str, `"last_name"`)
+}
+
+type fluentScopedPerson struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func Test_register_extension_for_types(t *testing.T) {
+	should := require.New(t)
+	ext := NewFluentExtension().Rename("Age", "years")
+	RegisterExtensionForTypes("jsoniter.fluentScopedPerson", ext)
+	obj := fluentScopedPerson{FirstName: "Ada", LastName: "Lovelace", Age: 36}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Contains(str, `"years":36`)
+}
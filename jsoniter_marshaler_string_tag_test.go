@@ -0,0 +1,103 @@
+package jsoniter
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/json-iterator/go/require"
+)
+
+type stringTagMarshaler struct {
+	val int
+}
+
+func (m stringTagMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(m.val)), nil
+}
+
+func (m *stringTagMarshaler) UnmarshalJSON(b []byte) error {
+	val, err := strconv.Atoi(string(b))
+	if err != nil {
+		return err
+	}
+	m.val = val
+	return nil
+}
+
+func Test_string_tag_with_marshaler(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Field stringTagMarshaler `json:",string"`
+	}
+	obj := TestObject{stringTagMarshaler{100}}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Equal(`{"Field":"100"}`, str)
+
+	var decoded TestObject
+	err = UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal(100, decoded.Field.val)
+}
+
+func Test_string_tag_with_pointer_marshaler(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Field *stringTagMarshaler `json:",string"`
+	}
+	obj := TestObject{&stringTagMarshaler{7}}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Equal(`{"Field":"7"}`, str)
+
+	var decoded TestObject
+	decoded.Field = &stringTagMarshaler{}
+	err = UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal(7, decoded.Field.val)
+}
+
+type stringTagIntMarshaler int
+
+func (m stringTagIntMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(m))), nil
+}
+
+func (m *stringTagIntMarshaler) UnmarshalJSON(b []byte) error {
+	val, err := strconv.Atoi(string(b))
+	if err != nil {
+		return err
+	}
+	*m = stringTagIntMarshaler(val)
+	return nil
+}
+
+// Test_string_tag_with_marshaler_int_kind covers a field whose underlying
+// kind (int) is one the stock `,string` tag handling already quote-wraps on
+// its own. decorateStringTagEncoder/Decoder must leave it to that stock
+// wrapping instead of quoting it a second time.
+func Test_string_tag_with_marshaler_int_kind(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Field stringTagIntMarshaler `json:",string"`
+	}
+	obj := TestObject{42}
+	str, err := MarshalToString(obj)
+	should.Nil(err)
+	should.Equal(`{"Field":"42"}`, str)
+
+	var decoded TestObject
+	err = UnmarshalFromString(str, &decoded)
+	should.Nil(err)
+	should.Equal(stringTagIntMarshaler(42), decoded.Field)
+}
+
+func Test_string_tag_with_marshaler_invalid_inner_json(t *testing.T) {
+	should := require.New(t)
+	type TestObject struct {
+		Field stringTagMarshaler `json:",string"`
+	}
+	var decoded TestObject
+	err := UnmarshalFromString(`{"Field":"not-a-number"}`, &decoded)
+	should.NotNil(err)
+}
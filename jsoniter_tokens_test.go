@@ -0,0 +1,83 @@
+package jsoniter
+
+import (
+	"testing"
+
+	"github.com/json-iterator/go/require"
+)
+
+func Test_iterator_tokens(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `{"a":1,"b":[true,null]}`)
+	ts := iter.Tokens()
+	var kinds []TokenKind
+	for {
+		token, ok := ts.Next()
+		if !ok {
+			break
+		}
+		kinds = append(kinds, token.Kind)
+	}
+	should.Nil(iter.Error)
+	should.Equal([]TokenKind{
+		TokenBeginObject,
+		TokenKey, TokenNumber,
+		TokenKey, TokenBeginArray, TokenBool, TokenNull, TokenEnd,
+		TokenEnd,
+	}, kinds)
+}
+
+func Test_stream_emit_tokens(t *testing.T) {
+	should := require.New(t)
+	iter := ParseString(ConfigDefault, `{"a":1,"b":[true,null]}`)
+	stream := NewStream(ConfigDefault, nil, 64)
+	err := stream.EmitTokens(iter.Tokens())
+	should.Nil(err)
+	should.Equal(`{"a":1,"b":[true,null]}`, string(stream.Buffer()))
+}
+
+func Test_decode_with_reentrant_callback(t *testing.T) {
+	should := require.New(t)
+	type seenEntry struct {
+		path string
+		kind TokenKind
+	}
+	var seen []seenEntry
+	var out []int
+	err := Decode([]byte(`[1,2,3]`), &out, DecodeOptions{
+		ReentrantCallback: func(path string, token Token) {
+			seen = append(seen, seenEntry{path, token.Kind})
+		},
+	})
+	should.Nil(err)
+	should.Nil(out)
+	should.Equal([]seenEntry{
+		{"", TokenBeginArray},
+		{"/0", TokenNumber},
+		{"/1", TokenNumber},
+		{"/2", TokenNumber},
+		{"", TokenEnd},
+	}, seen)
+}
+
+func Test_decode_with_reentrant_callback_nested_path(t *testing.T) {
+	should := require.New(t)
+	var paths []string
+	err := Decode([]byte(`{"items":[{"name":"a"}]}`), nil, DecodeOptions{
+		ReentrantCallback: func(path string, token Token) {
+			if token.Kind == TokenString {
+				paths = append(paths, path)
+			}
+		},
+	})
+	should.Nil(err)
+	should.Equal([]string{"/items/0/name"}, paths)
+}
+
+func Test_decode_without_callback_materializes_v(t *testing.T) {
+	should := require.New(t)
+	var out []int
+	err := Decode([]byte(`[1,2,3]`), &out, DecodeOptions{})
+	should.Nil(err)
+	should.Equal([]int{1, 2, 3}, out)
+}
@@ -0,0 +1,148 @@
+package jsoniter
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+var marshalerType = reflect2.TypeOfPtr((*json.Marshaler)(nil)).Elem()
+var unmarshalerType = reflect2.TypeOfPtr((*json.Unmarshaler)(nil)).Elem()
+
+func init() {
+	RegisterExtension(&stringTagMarshalerExtension{})
+}
+
+// stringTagMarshalerExtension is what actually puts decorateStringTagEncoder
+// and decorateStringTagDecoder in the compile path: it walks every struct
+// field during UpdateStructDescriptor (the same hook testExtension and
+// FluentExtension use) and rewraps the encoder/decoder of any field tagged
+// `,string` whose type (or *type) implements the JSON marshaler interfaces.
+type stringTagMarshalerExtension struct {
+	DummyExtension
+}
+
+func (ext *stringTagMarshalerExtension) UpdateStructDescriptor(structDescriptor *StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		if !hasStringTagOption(binding.Field.Tag().Get("json")) {
+			continue
+		}
+		typ := binding.Field.Type()
+		binding.Encoder = decorateStringTagEncoder(typ, binding.Encoder)
+		binding.Decoder = decorateStringTagDecoder(typ, binding.Decoder)
+	}
+}
+
+// stockHandlesStringTag reports whether kind is one of the field kinds that
+// processTags already rewraps for a `,string` tag on its own: a bool,
+// numeric or string field gets its stock encoder/decoder quote-wrapped
+// whether or not it also implements json.Marshaler/Unmarshaler. Decorating
+// one of those kinds here too would stack a second, conflicting quote layer
+// on top of the stock one, so decorateStringTagEncoder/Decoder skip them and
+// leave the stock wrapping in sole charge.
+func stockHandlesStringTag(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	}
+	return false
+}
+
+func hasStringTagOption(tag string) bool {
+	parts := strings.Split(tag, ",")
+	for _, part := range parts[1:] {
+		if part == "string" {
+			return true
+		}
+	}
+	return false
+}
+
+// decorateStringTagEncoder rewraps encoder, built for typ, so that a
+// `json:",string"` tagged field backed by a type implementing
+// json.Marshaler (or *type implementing it) is still emitted as a quoted
+// JSON string. Without this, the marshaler's raw output would be written
+// unquoted, breaking the `,string` contract. Kinds the stock `,string`
+// wrapping already covers (see stockHandlesStringTag) are left alone, since
+// that wrapping will already quote whatever this field's plain marshaler
+// encoder writes.
+func decorateStringTagEncoder(typ reflect2.Type, encoder ValEncoder) ValEncoder {
+	if stockHandlesStringTag(typ.Kind()) {
+		return encoder
+	}
+	if typ.Type1().Implements(marshalerType.Type1()) || reflect2.PtrTo(typ).Implements(marshalerType.Type1()) {
+		return &stringModeMarshalerEncoder{elemEncoder: encoder}
+	}
+	return encoder
+}
+
+// decorateStringTagDecoder is the decode-side counterpart of
+// decorateStringTagEncoder.
+func decorateStringTagDecoder(typ reflect2.Type, decoder ValDecoder) ValDecoder {
+	if stockHandlesStringTag(typ.Kind()) {
+		return decoder
+	}
+	if typ.Type1().Implements(unmarshalerType.Type1()) || reflect2.PtrTo(typ).Implements(unmarshalerType.Type1()) {
+		return &stringModeMarshalerDecoder{elemDecoder: decoder}
+	}
+	return decoder
+}
+
+// stringModeMarshalerEncoder wraps an encoder backed by a type's
+// MarshalJSON so that a `json:",string"` tagged field is still emitted as a
+// quoted JSON string, e.g. `"123"` instead of `123`.
+type stringModeMarshalerEncoder struct {
+	elemEncoder ValEncoder
+}
+
+func (encoder *stringModeMarshalerEncoder) Encode(ptr unsafe.Pointer, stream *Stream) {
+	tempStream := NewStream(stream.cfg, nil, 64)
+	tempStream.Attachment = stream.Attachment
+	encoder.elemEncoder.Encode(ptr, tempStream)
+	if tempStream.Error != nil && !errors.Is(tempStream.Error, io.EOF) && stream.Error == nil {
+		stream.Error = tempStream.Error
+		return
+	}
+	stream.WriteString(string(tempStream.Buffer()))
+}
+
+func (encoder *stringModeMarshalerEncoder) EncodeInterface(val interface{}, stream *Stream) {
+	WriteToStream(val, stream, encoder)
+}
+
+func (encoder *stringModeMarshalerEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return encoder.elemEncoder.IsEmpty(ptr)
+}
+
+// stringModeMarshalerDecoder wraps a decoder backed by a type's
+// UnmarshalJSON so that it can be paired with a `json:",string"` tagged
+// field: the outer JSON string is unquoted first, and the remaining bytes
+// are handed to elemDecoder exactly as if they had appeared unquoted.
+type stringModeMarshalerDecoder struct {
+	elemDecoder ValDecoder
+}
+
+func (decoder *stringModeMarshalerDecoder) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.WhatIsNext() == Nil {
+		iter.ReadNil()
+		return
+	}
+	raw := iter.ReadString()
+	if iter.Error != nil {
+		return
+	}
+	subIter := ParseString(iter.cfg, raw)
+	decoder.elemDecoder.Decode(ptr, subIter)
+	if subIter.Error != nil && !errors.Is(subIter.Error, io.EOF) {
+		iter.ReportError("stringModeMarshalerDecoder", "invalid json inside string tag: "+subIter.Error.Error())
+	}
+}
@@ -0,0 +1,168 @@
+package jsoniter
+
+import (
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// newTimeCodec builds the time.Time encoder/decoder pair for cfg, applying
+// Mode/Layout/Location/ParseLayouts so that each frozenConfig gets its own
+// codec instead of mutating ConfigDefault globally.
+func newTimeCodec(cfg Config) (ValEncoder, ValDecoder) {
+	loc := cfg.TimeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+	layout := cfg.TimeLayout
+	if cfg.TimeMode == TimeModeCustom && layout == "" {
+		layout = time.RFC3339Nano
+	}
+	codec := &timeCodec{
+		mode:         cfg.TimeMode,
+		layout:       layout,
+		loc:          loc,
+		parseLayouts: cfg.TimeParseLayouts,
+		zeroValue:    cfg.TimeZeroValue,
+	}
+	return codec, codec
+}
+
+type timeCodec struct {
+	mode         TimeMode
+	layout       string
+	loc          *time.Location
+	parseLayouts []string
+	zeroValue    TimeZeroValue
+}
+
+func (codec *timeCodec) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*(*time.Time)(ptr)).IsZero()
+}
+
+func (codec *timeCodec) EncodeInterface(val interface{}, stream *Stream) {
+	WriteToStream(val, stream, codec)
+}
+
+func (codec *timeCodec) Encode(ptr unsafe.Pointer, stream *Stream) {
+	val := *(*time.Time)(ptr)
+	if val.IsZero() {
+		if codec.zeroValue == TimeZeroEmptyString {
+			stream.WriteString("")
+		} else {
+			stream.WriteNil()
+		}
+		return
+	}
+	switch codec.mode {
+	case TimeModeUnix:
+		stream.WriteInt64(val.Unix())
+	case TimeModeUnixMilli:
+		stream.WriteInt64(val.UnixNano() / int64(time.Millisecond))
+	case TimeModeUnixNano:
+		stream.WriteInt64(val.UnixNano())
+	case TimeModeCustom:
+		stream.WriteString(val.In(codec.loc).Format(codec.layout))
+	default:
+		stream.WriteString(val.In(codec.loc).Format(time.RFC3339Nano))
+	}
+}
+
+func (codec *timeCodec) Decode(ptr unsafe.Pointer, iter *Iterator) {
+	if iter.WhatIsNext() == Nil {
+		iter.ReadNil()
+		*(*time.Time)(ptr) = time.Time{}
+		return
+	}
+	// An empty string decodes as the zero time regardless of mode, so that
+	// TimeZeroEmptyString-encoded output round-trips even for epoch modes.
+	if iter.WhatIsNext() == String {
+		switch codec.mode {
+		case TimeModeUnix, TimeModeUnixMilli, TimeModeUnixNano:
+			str := iter.ReadString()
+			if iter.Error != nil {
+				return
+			}
+			if str != "" {
+				iter.ReportError("timeCodec.Decode", "expected a number, got string "+strconv.Quote(str))
+				return
+			}
+			*(*time.Time)(ptr) = time.Time{}
+			return
+		}
+	}
+	switch codec.mode {
+	case TimeModeUnix:
+		*(*time.Time)(ptr) = time.Unix(iter.ReadInt64(), 0).In(codec.loc)
+		return
+	case TimeModeUnixMilli:
+		ms := iter.ReadInt64()
+		*(*time.Time)(ptr) = time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).In(codec.loc)
+		return
+	case TimeModeUnixNano:
+		ns := iter.ReadInt64()
+		*(*time.Time)(ptr) = time.Unix(0, ns).In(codec.loc)
+		return
+	}
+	str := iter.ReadString()
+	if iter.Error != nil {
+		return
+	}
+	if str == "" {
+		*(*time.Time)(ptr) = time.Time{}
+		return
+	}
+	layouts := codec.parseLayouts
+	primary := codec.layout
+	if codec.mode != TimeModeCustom {
+		primary = time.RFC3339Nano
+	}
+	val, err := time.ParseInLocation(primary, str, codec.loc)
+	if err != nil {
+		for _, layout := range layouts {
+			val, err = time.ParseInLocation(layout, str, codec.loc)
+			if err == nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		iter.ReportError("timeCodec.Decode", "invalid time "+strconv.Quote(str)+": "+err.Error())
+		return
+	}
+	*(*time.Time)(ptr) = val
+}
+
+var timeType = reflect2.TypeOf(time.Time{})
+
+// newTimeCodecExtension returns an Extension that hands encoder/decoder back
+// for time.Time only, so that it actually gets consulted by EncoderOf/
+// DecoderOf instead of sitting unused on frozenConfig. It is scoped to the
+// exact time.Time type: a named wrapper type implementing its own
+// MarshalJSON/UnmarshalJSON is a different reflect2.Type and so never
+// matches here, keeping that marshaler's output in charge as documented.
+func newTimeCodecExtension(encoder ValEncoder, decoder ValDecoder) Extension {
+	return &timeCodecExtension{encoder: encoder, decoder: decoder}
+}
+
+type timeCodecExtension struct {
+	DummyExtension
+	encoder ValEncoder
+	decoder ValDecoder
+}
+
+func (ext *timeCodecExtension) CreateEncoder(typ reflect2.Type) ValEncoder {
+	if typ == timeType {
+		return ext.encoder
+	}
+	return nil
+}
+
+func (ext *timeCodecExtension) CreateDecoder(typ reflect2.Type) ValDecoder {
+	if typ == timeType {
+		return ext.decoder
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package jsoniter
+
+// EmitTokens drains ts and writes each token back out as JSON, without ever
+// holding more than one token's worth of decoded data at a time. It is the
+// write-side complement to Iterator.Tokens(), useful for rewriting or
+// filtering a document as it streams through.
+func (stream *Stream) EmitTokens(ts *TokenStream) error {
+	needComma := map[int]bool{}
+	depth := 0
+	for {
+		token, ok := ts.Next()
+		if !ok {
+			break
+		}
+		if needComma[depth] && token.Kind != TokenEnd {
+			stream.WriteMore()
+		}
+		switch token.Kind {
+		case TokenBeginObject:
+			stream.WriteObjectStart()
+			depth++
+			needComma[depth] = false
+		case TokenBeginArray:
+			stream.WriteArrayStart()
+			depth++
+			needComma[depth] = false
+		case TokenEnd:
+			if token.Value == TokenBeginObject {
+				stream.WriteObjectEnd()
+			} else {
+				stream.WriteArrayEnd()
+			}
+			depth--
+			needComma[depth] = true
+		case TokenKey:
+			stream.WriteObjectField(token.Value.(string))
+			needComma[depth] = false
+		case TokenString:
+			stream.WriteString(token.Value.(string))
+			needComma[depth] = true
+		case TokenNumber:
+			stream.WriteFloat64(token.Value.(float64))
+			needComma[depth] = true
+		case TokenBool:
+			stream.WriteBool(token.Value.(bool))
+			needComma[depth] = true
+		case TokenNull:
+			stream.WriteNil()
+			needComma[depth] = true
+		}
+		if stream.Error != nil {
+			return stream.Error
+		}
+	}
+	return ts.iter.Error
+}